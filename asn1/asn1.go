@@ -0,0 +1,159 @@
+// Package asn1 provides PEM/DER round-tripping for keys, certificates, and
+// certificate signing requests built on the Go standard library's crypto
+// and x509/pkix packages.
+package asn1
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net"
+)
+
+const (
+	pemTypePrivateKey         = "PRIVATE KEY"
+	pemTypePublicKey          = "PUBLIC KEY"
+	pemTypeCertificate        = "CERTIFICATE"
+	pemTypeCertificateRequest = "CERTIFICATE REQUEST"
+)
+
+// PemEncode marshals a private key, public key, certificate, or certificate
+// signing request to PEM. Private keys are marshalled uniformly via PKCS#8
+// regardless of algorithm (RSA, ECDSA, Ed25519, ECDH). ctx parents the
+// encode span under the caller's trace; pass context.Background() if
+// there isn't one.
+func PemEncode(ctx context.Context, value any) (pemBytes []byte, err error) {
+	_, end := traceOperation(ctx, "asn1.pem_encode", fmt.Sprintf("%T", value))
+	defer func() { end(err) }()
+
+	switch typed := value.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, *ecdh.PrivateKey, ed25519.PrivateKey:
+		derBytes, err := x509.MarshalPKCS8PrivateKey(typed)
+		if err != nil {
+			return nil, fmt.Errorf("marshal private key failed: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: derBytes}), nil
+	case *rsa.PublicKey, *ecdsa.PublicKey, *ecdh.PublicKey, ed25519.PublicKey:
+		derBytes, err := x509.MarshalPKIXPublicKey(typed)
+		if err != nil {
+			return nil, fmt.Errorf("marshal public key failed: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: derBytes}), nil
+	case *x509.Certificate:
+		return pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: typed.Raw}), nil
+	case *x509.CertificateRequest:
+		return pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificateRequest, Bytes: typed.Raw}), nil
+	default:
+		return nil, fmt.Errorf("unsupported type for PEM encode: %T", value)
+	}
+}
+
+// PemDecode parses a single PEM block produced by PemEncode (or an
+// equivalent PKCS#8/PKIX/DER encoding) back into its concrete Go type.
+// ctx parents the decode span under the caller's trace; pass
+// context.Background() if there isn't one.
+func PemDecode(ctx context.Context, pemBytes []byte) (value any, err error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	_, end := traceOperation(ctx, "asn1.pem_decode", block.Type)
+	defer func() { end(err) }()
+
+	switch block.Type {
+	case pemTypePrivateKey:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse PKCS8 private key failed: %w", err)
+		}
+		return key, nil
+	case pemTypePublicKey:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse PKIX public key failed: %w", err)
+		}
+		return key, nil
+	case pemTypeCertificate:
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate failed: %w", err)
+		}
+		return certificate, nil
+	case pemTypeCertificateRequest:
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate request failed: %w", err)
+		}
+		return csr, nil
+	case "ENCRYPTED PRIVATE KEY":
+		return nil, fmt.Errorf("password required: use PemDecodeEncrypted for %s blocks", block.Type)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+	}
+}
+
+// BuildCertificateRequest creates and signs a PKCS#10 certificate signing
+// request for subject, carrying the given DNS names, email addresses, and
+// IP addresses as subject alternative names.
+func BuildCertificateRequest(subject pkix.Name, dnsNames []string, emails []string, ips []net.IP, key crypto.Signer) (*x509.CertificateRequest, error) {
+	template := &x509.CertificateRequest{
+		Subject:        subject,
+		DNSNames:       dnsNames,
+		EmailAddresses: emails,
+		IPAddresses:    ips,
+	}
+
+	csrDerBytes, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, fmt.Errorf("create certificate request failed: %w", err)
+	}
+
+	csr, err := x509.ParseCertificateRequest(csrDerBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse created certificate request failed: %w", err)
+	}
+
+	return csr, nil
+}
+
+// SignCertificateRequest issues a leaf certificate for csr, using template
+// for the fields x509.CreateCertificate needs beyond the CSR itself
+// (serial number, validity window, key usage, and so on), signed by caKey
+// on behalf of caCert. ctx parents the signing span under the caller's
+// trace; pass context.Background() if there isn't one.
+func SignCertificateRequest(ctx context.Context, csr *x509.CertificateRequest, caCert *x509.Certificate, caKey crypto.Signer, template *x509.Certificate) (leaf *x509.Certificate, err error) {
+	_, end := traceOperation(ctx, "asn1.sign_certificate_request", fmt.Sprintf("%T", caKey))
+	defer func() { end(err) }()
+
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("certificate request signature invalid: %w", err)
+	}
+
+	leafTemplate := *template
+	leafTemplate.Subject = csr.Subject
+	leafTemplate.DNSNames = csr.DNSNames
+	leafTemplate.EmailAddresses = csr.EmailAddresses
+	leafTemplate.IPAddresses = csr.IPAddresses
+	leafTemplate.URIs = csr.URIs
+
+	leafDerBytes, err := x509.CreateCertificate(rand.Reader, &leafTemplate, caCert, csr.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("create certificate from certificate request failed: %w", err)
+	}
+
+	leaf, err = x509.ParseCertificate(leafDerBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse issued certificate failed: %w", err)
+	}
+
+	return leaf, nil
+}
@@ -1,7 +1,9 @@
 package asn1
 
 import (
+	"bytes"
 	"context"
+	"crypto"
 	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/ed25519"
@@ -9,8 +11,10 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"log/slog"
 	"math/big"
+	"net"
 	"os"
 	"testing"
 	"time"
@@ -48,21 +52,21 @@ func TestPemEncodeDecodeRSA(t *testing.T) {
 	assert.IsType(t, &rsa.PrivateKey{}, privateKeyOriginal)
 	assert.IsType(t, &rsa.PublicKey{}, publicKeyOriginal)
 
-	privateKeyPemBytes, err := PemEncode(privateKeyOriginal)
+	privateKeyPemBytes, err := PemEncode(ctx, privateKeyOriginal)
 	assert.NoError(t, err)
 	slogger.Info("RSA Private", "pem", string(privateKeyPemBytes))
 
-	privateKeyDecoded, err := PemDecode(privateKeyPemBytes)
+	privateKeyDecoded, err := PemDecode(ctx, privateKeyPemBytes)
 	assert.NoError(t, err)
 
 	assert.IsType(t, &rsa.PrivateKey{}, privateKeyDecoded)
 	assert.Equal(t, privateKeyOriginal, privateKeyDecoded.(*rsa.PrivateKey))
 
-	publicKeyPemBytes, err := PemEncode(publicKeyOriginal)
+	publicKeyPemBytes, err := PemEncode(ctx, publicKeyOriginal)
 	assert.NoError(t, err)
 	slogger.Info("RSA Public", "pem", string(privateKeyPemBytes))
 
-	publicKeyDecoded, err := PemDecode(publicKeyPemBytes)
+	publicKeyDecoded, err := PemDecode(ctx, publicKeyPemBytes)
 	assert.NoError(t, err)
 
 	assert.IsType(t, &rsa.PublicKey{}, publicKeyDecoded)
@@ -77,21 +81,21 @@ func TestPemEncodeDecodeECDSA(t *testing.T) {
 	assert.IsType(t, &ecdsa.PrivateKey{}, privateKeyOriginal)
 	assert.IsType(t, &ecdsa.PublicKey{}, publicKeyOriginal)
 
-	privateKeyPemBytes, err := PemEncode(privateKeyOriginal)
+	privateKeyPemBytes, err := PemEncode(ctx, privateKeyOriginal)
 	assert.NoError(t, err)
 	slogger.Info("ECDSA Private", "pem", string(privateKeyPemBytes))
 
-	privateKeyDecoded, err := PemDecode(privateKeyPemBytes)
+	privateKeyDecoded, err := PemDecode(ctx, privateKeyPemBytes)
 	assert.NoError(t, err)
 
 	assert.IsType(t, &ecdsa.PrivateKey{}, privateKeyDecoded)
 	assert.Equal(t, privateKeyOriginal, privateKeyDecoded.(*ecdsa.PrivateKey))
 
-	publicKeyPemBytes, err := PemEncode(publicKeyOriginal)
+	publicKeyPemBytes, err := PemEncode(ctx, publicKeyOriginal)
 	assert.NoError(t, err)
 	slogger.Info("ECDSA Public", "pem", string(privateKeyPemBytes))
 
-	publicKeyDecoded, err := PemDecode(publicKeyPemBytes)
+	publicKeyDecoded, err := PemDecode(ctx, publicKeyPemBytes)
 	assert.NoError(t, err)
 
 	assert.IsType(t, &ecdsa.PublicKey{}, publicKeyDecoded)
@@ -107,21 +111,21 @@ func TestPemEncodeDecodeECDH(t *testing.T) {
 	assert.IsType(t, &ecdh.PrivateKey{}, privateKeyOriginal)
 	assert.IsType(t, &ecdh.PublicKey{}, publicKeyOriginal)
 
-	privateKeyPemBytes, err := PemEncode(privateKeyOriginal)
+	privateKeyPemBytes, err := PemEncode(ctx, privateKeyOriginal)
 	assert.NoError(t, err)
 	slogger.Info("ECDH Private", "pem", string(privateKeyPemBytes))
 
-	privateKeyDecoded, err := PemDecode(privateKeyPemBytes)
+	privateKeyDecoded, err := PemDecode(ctx, privateKeyPemBytes)
 	assert.NoError(t, err)
 
 	assert.IsType(t, &ecdh.PrivateKey{}, privateKeyDecoded)
 	assert.Equal(t, privateKeyOriginal, privateKeyDecoded.(*ecdh.PrivateKey))
 
-	publicKeyPemBytes, err := PemEncode(publicKeyOriginal)
+	publicKeyPemBytes, err := PemEncode(ctx, publicKeyOriginal)
 	assert.NoError(t, err)
 	slogger.Info("ECDH Public", "pem", string(privateKeyPemBytes))
 
-	publicKeyDecoded, err := PemDecode(publicKeyPemBytes)
+	publicKeyDecoded, err := PemDecode(ctx, publicKeyPemBytes)
 	assert.NoError(t, err)
 
 	assert.IsType(t, &ecdh.PublicKey{}, publicKeyDecoded)
@@ -134,27 +138,219 @@ func TestPemEncodeDecodeEdDSA(t *testing.T) {
 	assert.IsType(t, ed25519.PrivateKey{}, privateKeyOriginal)
 	assert.IsType(t, ed25519.PublicKey{}, publicKeyOriginal)
 
-	privateKeyPemBytes, err := PemEncode(privateKeyOriginal)
+	privateKeyPemBytes, err := PemEncode(ctx, privateKeyOriginal)
 	assert.NoError(t, err)
 	slogger.Info("ED Private", "pem", string(privateKeyPemBytes))
 
-	privateKeyDecoded, err := PemDecode(privateKeyPemBytes)
+	privateKeyDecoded, err := PemDecode(ctx, privateKeyPemBytes)
 	assert.NoError(t, err)
 
 	assert.IsType(t, ed25519.PrivateKey{}, privateKeyDecoded)
 	assert.Equal(t, privateKeyOriginal, privateKeyDecoded.(ed25519.PrivateKey))
 
-	publicKeyPemBytes, err := PemEncode(publicKeyOriginal)
+	publicKeyPemBytes, err := PemEncode(ctx, publicKeyOriginal)
 	assert.NoError(t, err)
 	slogger.Info("ED Public", "pem", string(privateKeyPemBytes))
 
-	publicKeyDecoded, err := PemDecode(publicKeyPemBytes)
+	publicKeyDecoded, err := PemDecode(ctx, publicKeyPemBytes)
 	assert.NoError(t, err)
 
 	assert.IsType(t, ed25519.PublicKey{}, publicKeyDecoded)
 	assert.Equal(t, publicKeyOriginal, publicKeyDecoded.(ed25519.PublicKey))
 }
 
+func TestPemEncodeDecodeEncryptedRSA(t *testing.T) {
+	keyPairOriginal, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	password := []byte("correct horse battery staple")
+
+	encryptedPemBytes, err := PemEncodeEncrypted(ctx, keyPairOriginal, password, nil)
+	assert.NoError(t, err)
+	slogger.Info("RSA Encrypted Private", "pem", string(encryptedPemBytes))
+
+	privateKeyDecoded, err := PemDecodeEncrypted(ctx, encryptedPemBytes, password)
+	assert.NoError(t, err)
+	assert.IsType(t, &rsa.PrivateKey{}, privateKeyDecoded)
+	assert.Equal(t, keyPairOriginal, privateKeyDecoded.(*rsa.PrivateKey))
+
+	_, err = PemDecodeEncrypted(ctx, encryptedPemBytes, []byte("wrong password"))
+	assert.Error(t, err)
+}
+
+func TestPemEncodeDecodeEncryptedECDSA(t *testing.T) {
+	keyPairOriginal, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	password := []byte("correct horse battery staple")
+
+	encryptedPemBytes, err := PemEncodeEncrypted(ctx, keyPairOriginal, password, nil)
+	assert.NoError(t, err)
+	slogger.Info("ECDSA Encrypted Private", "pem", string(encryptedPemBytes))
+
+	privateKeyDecoded, err := PemDecodeEncrypted(ctx, encryptedPemBytes, password)
+	assert.NoError(t, err)
+	assert.IsType(t, &ecdsa.PrivateKey{}, privateKeyDecoded)
+	assert.Equal(t, keyPairOriginal, privateKeyDecoded.(*ecdsa.PrivateKey))
+
+	_, err = PemDecodeEncrypted(ctx, encryptedPemBytes, []byte("wrong password"))
+	assert.Error(t, err)
+}
+
+func TestPemEncodeDecodeEncryptedEdDSA(t *testing.T) {
+	_, privateKeyOriginal, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	password := []byte("correct horse battery staple")
+
+	encryptedPemBytes, err := PemEncodeEncrypted(ctx, privateKeyOriginal, password, nil)
+	assert.NoError(t, err)
+	slogger.Info("ED Encrypted Private", "pem", string(encryptedPemBytes))
+
+	privateKeyDecoded, err := PemDecodeEncrypted(ctx, encryptedPemBytes, password)
+	assert.NoError(t, err)
+	assert.IsType(t, ed25519.PrivateKey{}, privateKeyDecoded)
+	assert.Equal(t, privateKeyOriginal, privateKeyDecoded.(ed25519.PrivateKey))
+
+	_, err = PemDecodeEncrypted(ctx, encryptedPemBytes, []byte("wrong password"))
+	assert.Error(t, err)
+}
+
+func TestPemEncodeDecodeEncryptedECDH(t *testing.T) {
+	t.Skip("Blocked by bug: https://github.com/golang/go/issues/71919")
+	keyPairOriginal, err := ecdh.P256().GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	password := []byte("correct horse battery staple")
+
+	encryptedPemBytes, err := PemEncodeEncrypted(ctx, keyPairOriginal, password, nil)
+	assert.NoError(t, err)
+	slogger.Info("ECDH Encrypted Private", "pem", string(encryptedPemBytes))
+
+	privateKeyDecoded, err := PemDecodeEncrypted(ctx, encryptedPemBytes, password)
+	assert.NoError(t, err)
+	assert.IsType(t, &ecdh.PrivateKey{}, privateKeyDecoded)
+	assert.Equal(t, keyPairOriginal, privateKeyDecoded.(*ecdh.PrivateKey))
+
+	_, err = PemDecodeEncrypted(ctx, encryptedPemBytes, []byte("wrong password"))
+	assert.Error(t, err)
+}
+
+func TestPemDecodeRejectsEncryptedPrivateKey(t *testing.T) {
+	keyPairOriginal, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	encryptedPemBytes, err := PemEncodeEncrypted(ctx, keyPairOriginal, []byte("password"), nil)
+	assert.NoError(t, err)
+
+	_, err = PemDecode(ctx, encryptedPemBytes)
+	assert.Error(t, err)
+}
+
+func TestPublicKeyFingerprintString(t *testing.T) {
+	ecdsaKeyPair, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	rsaKeyPair, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	edPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	publicKeys := map[string]any{
+		"ECDSA": &ecdsaKeyPair.PublicKey,
+		"RSA":   &rsaKeyPair.PublicKey,
+		"EdDSA": edPublicKey,
+	}
+
+	for name, publicKey := range publicKeys {
+		fingerprint, err := PublicKeyFingerprintString(ctx, publicKey, crypto.SHA256)
+		assert.NoError(t, err)
+		slogger.Info("Fingerprint", "type", name, "value", fingerprint)
+		assert.Contains(t, fingerprint, "sha256:")
+	}
+}
+
+func TestPublicKeyFingerprintStringECDH(t *testing.T) {
+	t.Skip("Blocked by bug: https://github.com/golang/go/issues/71919")
+	keyPairOriginal, err := ecdh.P256().GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	fingerprint, err := PublicKeyFingerprintString(ctx, keyPairOriginal.PublicKey(), crypto.SHA256)
+	assert.NoError(t, err)
+	slogger.Info("Fingerprint", "type", "ECDH", "value", fingerprint)
+	assert.Contains(t, fingerprint, "sha256:")
+}
+
+func TestCertificateSPKIFingerprint(t *testing.T) {
+	keyPair, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	certificate, _, err := NewSelfSigned(
+		ctx,
+		NewCertBuilder().Subject(pkix.Name{CommonName: "fingerprint-test"}).IsCA(true).NotBeforeAfter(time.Now(), 24*time.Hour),
+		keyPair,
+	)
+	assert.NoError(t, err)
+
+	certificateFingerprint, err := CertificateSPKIFingerprint(ctx, certificate, crypto.SHA256)
+	assert.NoError(t, err)
+
+	publicKeyFingerprint, err := PublicKeyFingerprint(ctx, &keyPair.PublicKey, crypto.SHA256)
+	assert.NoError(t, err)
+
+	assert.Equal(t, publicKeyFingerprint, certificateFingerprint)
+}
+
+func TestPublicKeysEqual(t *testing.T) {
+	keyPairOriginal, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	equal, err := PublicKeysEqual(&keyPairOriginal.PublicKey, &keyPairOriginal.PublicKey)
+	assert.NoError(t, err)
+	assert.True(t, equal)
+
+	otherKeyPair, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	equal, err = PublicKeysEqual(&keyPairOriginal.PublicKey, &otherKeyPair.PublicKey)
+	assert.NoError(t, err)
+	assert.False(t, equal)
+}
+
+func TestCertBuilderChain(t *testing.T) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	rootCert, rootPemBytes, err := NewSelfSigned(
+		ctx,
+		NewCertBuilder().Subject(pkix.Name{CommonName: "root"}).IsCA(true).NotBeforeAfter(time.Now(), 24*time.Hour),
+		rootKey,
+	)
+	assert.NoError(t, err)
+
+	intermediateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	intermediateCert, intermediatePemBytes, err := NewIntermediateCA(
+		ctx, rootCert, rootKey,
+		NewCertBuilder().Subject(pkix.Name{CommonName: "intermediate"}).NotBeforeAfter(time.Now(), 24*time.Hour),
+		&intermediateKey.PublicKey,
+	)
+	assert.NoError(t, err)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, leafPemBytes, err := NewCertBuilder().
+		Subject(pkix.Name{CommonName: "leaf"}).
+		DNS("example.com").
+		KeyUsage(x509.KeyUsageDigitalSignature).
+		ExtKeyUsage(x509.ExtKeyUsageServerAuth).
+		NotBeforeAfter(time.Now(), 24*time.Hour).
+		Sign(ctx, intermediateCert, intermediateKey, &leafKey.PublicKey)
+	assert.NoError(t, err)
+
+	chain := Chain{Leaf: leafPemBytes, Intermediates: [][]byte{intermediatePemBytes}, Root: rootPemBytes}
+	bundle := chain.PEMBundle()
+	slogger.Info("Chain", "pem", string(bundle))
+
+	assert.True(t, bytes.HasPrefix(bundle, leafPemBytes))
+	assert.Contains(t, string(bundle), string(intermediatePemBytes))
+	assert.True(t, bytes.HasSuffix(bundle, rootPemBytes))
+}
+
 func TestPemEncodeDecodeCertificate(t *testing.T) {
 	privateKeyOriginal, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	assert.NoError(t, err)
@@ -169,13 +365,60 @@ func TestPemEncodeDecodeCertificate(t *testing.T) {
 	certificateOriginal, err := x509.ParseCertificate(certificateDerBytes)
 	assert.NoError(t, err)
 
-	certificatePemBytes, err := PemEncode(certificateOriginal)
+	certificatePemBytes, err := PemEncode(ctx, certificateOriginal)
 	assert.NoError(t, err)
 	slogger.Info("Cert", "pem", string(certificatePemBytes))
 
-	certificateDecoded, err := PemDecode(certificatePemBytes)
+	certificateDecoded, err := PemDecode(ctx, certificatePemBytes)
 	assert.NoError(t, err)
 
 	assert.IsType(t, &x509.Certificate{}, certificateDecoded)
 	assert.Equal(t, certificateOriginal, certificateDecoded.(*x509.Certificate))
 }
+
+func TestBuildAndSignCertificateRequest(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caCert, _, err := NewSelfSigned(
+		ctx,
+		NewCertBuilder().Subject(pkix.Name{CommonName: "ca"}).IsCA(true).NotBeforeAfter(time.Now(), 24*time.Hour),
+		caKey,
+	)
+	assert.NoError(t, err)
+
+	subjectKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	csr, err := BuildCertificateRequest(
+		pkix.Name{CommonName: "example.com"},
+		[]string{"example.com"},
+		[]string{"admin@example.com"},
+		[]net.IP{net.ParseIP("127.0.0.1")},
+		subjectKey,
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, csr.CheckSignature())
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	leaf, err := SignCertificateRequest(ctx, csr, caCert, caKey, leafTemplate)
+	assert.NoError(t, err)
+	assert.NoError(t, leaf.CheckSignatureFrom(caCert))
+	assert.Equal(t, "example.com", leaf.Subject.CommonName)
+	assert.Contains(t, leaf.DNSNames, "example.com")
+
+	leafPemBytes, err := PemEncode(ctx, leaf)
+	assert.NoError(t, err)
+	slogger.Info("Leaf", "pem", string(leafPemBytes))
+
+	leafDecoded, err := PemDecode(ctx, leafPemBytes)
+	assert.NoError(t, err)
+	assert.IsType(t, &x509.Certificate{}, leafDecoded)
+	assert.Equal(t, leaf, leafDecoded.(*x509.Certificate))
+}
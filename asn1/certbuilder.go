@@ -0,0 +1,167 @@
+package asn1
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"time"
+)
+
+// CertBuilder assembles an x509.Certificate template through chained
+// setter calls, then issues it with Sign (or NewSelfSigned /
+// NewIntermediateCA). Replaces the ad-hoc literal-struct pattern that
+// tests previously had to assemble by hand.
+type CertBuilder struct {
+	template x509.Certificate
+}
+
+// NewCertBuilder starts a CertBuilder with a random 128-bit serial
+// number already assigned.
+func NewCertBuilder() *CertBuilder {
+	builder := &CertBuilder{}
+	builder.SerialRandom()
+	return builder
+}
+
+// Subject sets the certificate's subject name.
+func (b *CertBuilder) Subject(subject pkix.Name) *CertBuilder {
+	b.template.Subject = subject
+	return b
+}
+
+// DNS appends DNS subject alternative names.
+func (b *CertBuilder) DNS(dnsNames ...string) *CertBuilder {
+	b.template.DNSNames = append(b.template.DNSNames, dnsNames...)
+	return b
+}
+
+// IP appends IP address subject alternative names.
+func (b *CertBuilder) IP(ips ...net.IP) *CertBuilder {
+	b.template.IPAddresses = append(b.template.IPAddresses, ips...)
+	return b
+}
+
+// Email appends email address subject alternative names.
+func (b *CertBuilder) Email(emails ...string) *CertBuilder {
+	b.template.EmailAddresses = append(b.template.EmailAddresses, emails...)
+	return b
+}
+
+// URI appends URI subject alternative names.
+func (b *CertBuilder) URI(uris ...*url.URL) *CertBuilder {
+	b.template.URIs = append(b.template.URIs, uris...)
+	return b
+}
+
+// NotBeforeAfter sets the validity window to [notBefore, notBefore+validity).
+func (b *CertBuilder) NotBeforeAfter(notBefore time.Time, validity time.Duration) *CertBuilder {
+	b.template.NotBefore = notBefore
+	b.template.NotAfter = notBefore.Add(validity)
+	return b
+}
+
+// KeyUsage sets the certificate's key usage bits.
+func (b *CertBuilder) KeyUsage(keyUsage x509.KeyUsage) *CertBuilder {
+	b.template.KeyUsage = keyUsage
+	return b
+}
+
+// ExtKeyUsage appends extended key usages.
+func (b *CertBuilder) ExtKeyUsage(extKeyUsage ...x509.ExtKeyUsage) *CertBuilder {
+	b.template.ExtKeyUsage = append(b.template.ExtKeyUsage, extKeyUsage...)
+	return b
+}
+
+// IsCA marks the certificate as a CA (or not) and makes the basic
+// constraints extension explicit either way.
+func (b *CertBuilder) IsCA(isCA bool) *CertBuilder {
+	b.template.IsCA = isCA
+	b.template.BasicConstraintsValid = true
+	return b
+}
+
+// PathLen sets the CA path length constraint.
+func (b *CertBuilder) PathLen(pathLen int) *CertBuilder {
+	b.template.MaxPathLen = pathLen
+	b.template.MaxPathLenZero = pathLen == 0
+	return b
+}
+
+// SerialRandom assigns a new random 128-bit serial number. NewCertBuilder
+// already calls this; it's exported so callers can re-roll explicitly.
+func (b *CertBuilder) SerialRandom() *CertBuilder {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		panic(fmt.Errorf("generate random serial number failed: %w", err))
+	}
+	b.template.SerialNumber = serial
+	return b
+}
+
+// Sign issues the accumulated template, signed by issuerKey on behalf of
+// issuer, for subjectPub, and returns both the parsed certificate and its
+// PEM encoding. ctx parents the encode span under the caller's trace;
+// pass context.Background() if there isn't one.
+func (b *CertBuilder) Sign(ctx context.Context, issuer *x509.Certificate, issuerKey crypto.Signer, subjectPub crypto.PublicKey) (certificate *x509.Certificate, certificatePemBytes []byte, err error) {
+	derBytes, err := x509.CreateCertificate(rand.Reader, &b.template, issuer, subjectPub, issuerKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create certificate failed: %w", err)
+	}
+
+	certificate, err = x509.ParseCertificate(derBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse issued certificate failed: %w", err)
+	}
+
+	certificatePemBytes, err = PemEncode(ctx, certificate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode issued certificate failed: %w", err)
+	}
+
+	return certificate, certificatePemBytes, nil
+}
+
+// NewSelfSigned issues builder's template signed by key against itself,
+// the shortcut for root CAs and standalone leaf certificates.
+func NewSelfSigned(ctx context.Context, builder *CertBuilder, key crypto.Signer) (certificate *x509.Certificate, certificatePemBytes []byte, err error) {
+	return builder.Sign(ctx, &builder.template, key, key.Public())
+}
+
+// NewIntermediateCA issues builder's template as a CA certificate signed
+// by parentKey on behalf of parent, for subjectPub.
+func NewIntermediateCA(ctx context.Context, parent *x509.Certificate, parentKey crypto.Signer, builder *CertBuilder, subjectPub crypto.PublicKey) (certificate *x509.Certificate, certificatePemBytes []byte, err error) {
+	builder.IsCA(true)
+	return builder.Sign(ctx, parent, parentKey, subjectPub)
+}
+
+// Chain bundles a leaf certificate with its issuing intermediates and
+// root, each already PEM-encoded, in presentation order (leaf first).
+type Chain struct {
+	Leaf          []byte
+	Intermediates [][]byte
+	Root          []byte
+}
+
+// PEMBundle concatenates leaf, intermediates, and root PEM blocks in
+// order, ready for tls.X509KeyPair or writing straight to a bundle file.
+func (c Chain) PEMBundle() []byte {
+	capacity := len(c.Leaf) + len(c.Root)
+	for _, intermediate := range c.Intermediates {
+		capacity += len(intermediate)
+	}
+
+	bundle := make([]byte, 0, capacity)
+	bundle = append(bundle, c.Leaf...)
+	for _, intermediate := range c.Intermediates {
+		bundle = append(bundle, intermediate...)
+	}
+	bundle = append(bundle, c.Root...)
+
+	return bundle
+}
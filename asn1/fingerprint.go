@@ -0,0 +1,82 @@
+package asn1
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+)
+
+// PublicKeyFingerprint hashes the DER-encoded SPKI (SubjectPublicKeyInfo)
+// of key with hash — the same construction HPKP and certificate pinning
+// use. RSA, ECDSA, Ed25519, and ECDH public keys are all supported since
+// they all marshal uniformly via x509.MarshalPKIXPublicKey. ctx parents
+// the span under the caller's trace; pass context.Background() if there
+// isn't one.
+func PublicKeyFingerprint(ctx context.Context, key any, hash crypto.Hash) (fingerprint []byte, err error) {
+	_, end := traceOperation(ctx, "asn1.public_key_fingerprint", fmt.Sprintf("%T", key))
+	defer func() { end(err) }()
+
+	if !hash.Available() {
+		return nil, fmt.Errorf("hash algorithm %s is not available", hash)
+	}
+
+	spkiDerBytes, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal public key failed: %w", err)
+	}
+
+	hasher := hash.New()
+	hasher.Write(spkiDerBytes)
+
+	return hasher.Sum(nil), nil
+}
+
+// PublicKeyFingerprintString returns PublicKeyFingerprint formatted as
+// "<hash>:<base64>", e.g. "sha256:BASE64...".
+func PublicKeyFingerprintString(ctx context.Context, key any, hash crypto.Hash) (string, error) {
+	fingerprint, err := PublicKeyFingerprint(ctx, key, hash)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s:%s", hashName(hash), base64.StdEncoding.EncodeToString(fingerprint)), nil
+}
+
+// PublicKeysEqual reports whether a and b marshal to identical PKIX SPKI
+// bytes, so e.g. two *rsa.PublicKey values built from different sources
+// but with the same modulus and exponent compare equal.
+func PublicKeysEqual(a any, b any) (bool, error) {
+	aDerBytes, err := x509.MarshalPKIXPublicKey(a)
+	if err != nil {
+		return false, fmt.Errorf("marshal first public key failed: %w", err)
+	}
+
+	bDerBytes, err := x509.MarshalPKIXPublicKey(b)
+	if err != nil {
+		return false, fmt.Errorf("marshal second public key failed: %w", err)
+	}
+
+	return bytes.Equal(aDerBytes, bDerBytes), nil
+}
+
+// CertificateSPKIFingerprint fingerprints certificate's public key,
+// convenient for building pin sets from a certificate chain.
+func CertificateSPKIFingerprint(ctx context.Context, certificate *x509.Certificate, hash crypto.Hash) ([]byte, error) {
+	return PublicKeyFingerprint(ctx, certificate.PublicKey, hash)
+}
+
+func hashName(hash crypto.Hash) string {
+	switch hash {
+	case crypto.SHA256:
+		return "sha256"
+	case crypto.SHA384:
+		return "sha384"
+	case crypto.SHA512:
+		return "sha512"
+	default:
+		return hash.String()
+	}
+}
@@ -0,0 +1,44 @@
+package asn1
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "cryptoutil/asn1"
+
+var (
+	tracer = otel.Tracer(instrumentationName)
+	meter  = otel.Meter(instrumentationName)
+
+	operationCounter, _  = meter.Int64Counter("asn1.operation.count")
+	operationDuration, _ = meter.Float64Histogram("asn1.operation.duration_seconds")
+)
+
+// traceOperation starts a span named operation as a child of ctx, tagged
+// with keyType, and returns the span's context plus a completion func
+// that counts the operation and records its duration as metrics (tagged
+// by operation and key type) and, on error, marks the span failed.
+// Callers defer end(err) with their named error return.
+func traceOperation(ctx context.Context, operation string, keyType string) (context.Context, func(err error)) {
+	ctx, span := tracer.Start(ctx, operation, trace.WithAttributes(attribute.String("key_type", keyType)))
+	start := time.Now()
+
+	return ctx, func(err error) {
+		attrs := metric.WithAttributes(attribute.String("operation", operation), attribute.String("key_type", keyType))
+		operationCounter.Add(ctx, 1, attrs)
+		operationDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
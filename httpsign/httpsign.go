@@ -0,0 +1,289 @@
+// Package httpsign signs and verifies HTTP requests using the key types
+// produced by the asn1 package: a client attaches a detached signature
+// over a canonical summary of the request, and a server reconstructs the
+// same summary to verify it against the claimed key.
+package httpsign
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cryptoutil/asn1"
+)
+
+// DefaultMaxSkew is the timestamp tolerance Verifier applies unless
+// Verifier.MaxSkew overrides it.
+const DefaultMaxSkew = 5 * time.Minute
+
+const (
+	headerContentHash   = "X-Content-Hash"
+	headerTimestamp     = "X-Timestamp"
+	headerKeyID         = "X-Key-Id"
+	headerAuthorization = "X-Authorization"
+)
+
+// RequestSigner attaches a detached signature to outgoing *http.Request
+// values using a key loaded from PEM.
+type RequestSigner struct {
+	keyID     string
+	signer    crypto.Signer
+	algorithm string
+}
+
+// NewRequestSigner decodes privateKeyPEM via asn1.PemDecode and selects a
+// signing algorithm from the key's type (and, for ECDSA, its curve):
+// ECDSA keys sign as "ecdsa-p256-sha256" / "-p384-sha384" / "-p521-sha512",
+// Ed25519 keys sign as "ed25519", and RSA keys sign as "rsa-pss-sha256".
+// ctx parents the decode span under the caller's trace; pass
+// context.Background() if there isn't one.
+func NewRequestSigner(ctx context.Context, privateKeyPEM []byte, keyID string) (*RequestSigner, error) {
+	key, err := asn1.PemDecode(ctx, privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("decode signing key failed: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key of type %T does not implement crypto.Signer", key)
+	}
+
+	algorithm, err := algorithmForKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestSigner{keyID: keyID, signer: signer, algorithm: algorithm}, nil
+}
+
+// SignRequest computes the SHA-256 of req's body, sets X-Content-Hash,
+// builds the canonical string over (timestamp, method, URL, contentHash),
+// signs it, and attaches X-Timestamp, X-Key-Id, and X-Authorization
+// (base64 signature prefixed with the algorithm token).
+func (s *RequestSigner) SignRequest(req *http.Request) error {
+	bodyBytes, err := drainBody(req)
+	if err != nil {
+		return err
+	}
+
+	contentHash := contentHashOf(bodyBytes)
+	req.Header.Set(headerContentHash, contentHash)
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	canonical := canonicalString(timestamp, req.Method, req.URL.String(), contentHash)
+
+	signature, err := signCanonical(s.signer, s.algorithm, []byte(canonical))
+	if err != nil {
+		return fmt.Errorf("sign request failed: %w", err)
+	}
+
+	req.Header.Set(headerTimestamp, timestamp)
+	req.Header.Set(headerKeyID, s.keyID)
+	req.Header.Set(headerAuthorization, fmt.Sprintf("%s %s", s.algorithm, base64.StdEncoding.EncodeToString(signature)))
+
+	return nil
+}
+
+// KeyResolver looks up the public key a Verifier should use for the
+// X-Key-Id value attached to a request.
+type KeyResolver func(keyID string) (crypto.PublicKey, error)
+
+// Verifier checks the signature a RequestSigner attached, resolving the
+// verification key by keyID and rejecting requests whose timestamp has
+// drifted by more than MaxSkew.
+type Verifier struct {
+	resolveKey KeyResolver
+	maxSkew    time.Duration
+}
+
+// NewVerifier builds a Verifier that resolves keys with resolveKey and
+// allows DefaultMaxSkew of clock drift; use MaxSkew to override it.
+func NewVerifier(resolveKey KeyResolver) *Verifier {
+	return &Verifier{resolveKey: resolveKey, maxSkew: DefaultMaxSkew}
+}
+
+// MaxSkew overrides the allowed timestamp drift and returns the Verifier
+// for chaining.
+func (v *Verifier) MaxSkew(maxSkew time.Duration) *Verifier {
+	v.maxSkew = maxSkew
+	return v
+}
+
+// VerifyRequest reconstructs the canonical string SignRequest produced
+// and checks it against the request's X-Authorization signature.
+func (v *Verifier) VerifyRequest(req *http.Request) error {
+	timestampHeader := req.Header.Get(headerTimestamp)
+	keyID := req.Header.Get(headerKeyID)
+	authHeader := req.Header.Get(headerAuthorization)
+
+	algorithm, signatureB64, ok := strings.Cut(authHeader, " ")
+	if !ok {
+		return fmt.Errorf("malformed %s header", headerAuthorization)
+	}
+
+	if err := v.checkSkew(timestampHeader); err != nil {
+		return err
+	}
+
+	bodyBytes, err := drainBody(req)
+	if err != nil {
+		return err
+	}
+
+	contentHash := contentHashOf(bodyBytes)
+	if req.Header.Get(headerContentHash) != contentHash {
+		return fmt.Errorf("%s does not match request body", headerContentHash)
+	}
+
+	publicKey, err := v.resolveKey(keyID)
+	if err != nil {
+		return fmt.Errorf("resolve key %q failed: %w", keyID, err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signatureB64)
+	if err != nil {
+		return fmt.Errorf("decode signature failed: %w", err)
+	}
+
+	canonical := canonicalString(timestampHeader, req.Method, req.URL.String(), contentHash)
+
+	return verifySignature(publicKey, algorithm, []byte(canonical), signature)
+}
+
+func (v *Verifier) checkSkew(timestampHeader string) error {
+	timestampUnix, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse %s header failed: %w", headerTimestamp, err)
+	}
+
+	skew := time.Since(time.Unix(timestampUnix, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > v.maxSkew {
+		return fmt.Errorf("timestamp skew %s exceeds max allowed %s", skew, v.maxSkew)
+	}
+
+	return nil
+}
+
+func drainBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	bodyBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body failed: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+
+	return bodyBytes, nil
+}
+
+func contentHashOf(bodyBytes []byte) string {
+	sum := sha256.Sum256(bodyBytes)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func canonicalString(timestamp string, method string, url string, contentHash string) string {
+	return strings.Join([]string{timestamp, method, url, contentHash}, "\n")
+}
+
+func algorithmForKey(key any) (string, error) {
+	switch typed := key.(type) {
+	case *ecdsa.PrivateKey:
+		return ecdsaAlgorithmForCurve(typed.Curve)
+	case ed25519.PrivateKey:
+		return "ed25519", nil
+	case *rsa.PrivateKey:
+		return "rsa-pss-sha256", nil
+	default:
+		return "", fmt.Errorf("unsupported key type for request signing: %T", key)
+	}
+}
+
+func ecdsaAlgorithmForCurve(curve elliptic.Curve) (string, error) {
+	switch curve {
+	case elliptic.P256():
+		return "ecdsa-p256-sha256", nil
+	case elliptic.P384():
+		return "ecdsa-p384-sha384", nil
+	case elliptic.P521():
+		return "ecdsa-p521-sha512", nil
+	default:
+		return "", fmt.Errorf("unsupported ECDSA curve for request signing: %s", curve.Params().Name)
+	}
+}
+
+func hashForAlgorithm(algorithm string) crypto.Hash {
+	switch algorithm {
+	case "ecdsa-p384-sha384":
+		return crypto.SHA384
+	case "ecdsa-p521-sha512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+func signCanonical(signer crypto.Signer, algorithm string, canonical []byte) ([]byte, error) {
+	if algorithm == "ed25519" {
+		return signer.Sign(rand.Reader, canonical, crypto.Hash(0))
+	}
+
+	hash := hashForAlgorithm(algorithm)
+	hasher := hash.New()
+	hasher.Write(canonical)
+	digest := hasher.Sum(nil)
+
+	if algorithm == "rsa-pss-sha256" {
+		return signer.Sign(rand.Reader, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash})
+	}
+
+	return signer.Sign(rand.Reader, digest, hash)
+}
+
+func verifySignature(publicKey crypto.PublicKey, algorithm string, canonical []byte, signature []byte) error {
+	switch typed := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		digest := digestOf(hashForAlgorithm(algorithm), canonical)
+		if !ecdsa.VerifyASN1(typed, digest, signature) {
+			return fmt.Errorf("ecdsa signature verification failed")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(typed, canonical, signature) {
+			return fmt.Errorf("ed25519 signature verification failed")
+		}
+		return nil
+	case *rsa.PublicKey:
+		hash := hashForAlgorithm(algorithm)
+		digest := digestOf(hash, canonical)
+		if err := rsa.VerifyPSS(typed, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}); err != nil {
+			return fmt.Errorf("rsa-pss signature verification failed: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type for verification: %T", publicKey)
+	}
+}
+
+func digestOf(hash crypto.Hash, canonical []byte) []byte {
+	hasher := hash.New()
+	hasher.Write(canonical)
+	return hasher.Sum(nil)
+}
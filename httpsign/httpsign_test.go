@@ -0,0 +1,136 @@
+package httpsign
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"cryptoutil/asn1"
+	"cryptoutil/telemetry"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx     context.Context
+	slogger *slog.Logger
+)
+
+func TestMain(m *testing.M) {
+	startTime := time.Now()
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	telemetryService := telemetry.Init(ctx, startTime, "httpsign_test", false, false)
+	telemetry.Shutdown(telemetryService)
+	slogger = telemetryService.Slogger
+
+	rc := m.Run()
+	os.Exit(rc)
+}
+
+func TestSignAndVerifyRequestECDSA(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	privateKeyPemBytes, err := asn1.PemEncode(ctx, privateKey)
+	assert.NoError(t, err)
+
+	signer, err := NewRequestSigner(ctx, privateKeyPemBytes, "key-1")
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/widgets", strings.NewReader(`{"name":"widget"}`))
+	assert.NoError(t, err)
+
+	assert.NoError(t, signer.SignRequest(req))
+	assert.NotEmpty(t, req.Header.Get(headerAuthorization))
+	slogger.Info("signed request", "authorization", req.Header.Get(headerAuthorization))
+
+	verifier := NewVerifier(func(keyID string) (crypto.PublicKey, error) {
+		assert.Equal(t, "key-1", keyID)
+		return &privateKey.PublicKey, nil
+	})
+
+	assert.NoError(t, verifier.VerifyRequest(req))
+}
+
+func TestSignAndVerifyRequestEd25519(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	privateKeyPemBytes, err := asn1.PemEncode(ctx, privateKey)
+	assert.NoError(t, err)
+
+	signer, err := NewRequestSigner(ctx, privateKeyPemBytes, "key-1")
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/widgets", strings.NewReader(`{"name":"widget"}`))
+	assert.NoError(t, err)
+
+	assert.NoError(t, signer.SignRequest(req))
+	assert.True(t, strings.HasPrefix(req.Header.Get(headerAuthorization), "ed25519 "))
+
+	verifier := NewVerifier(func(keyID string) (crypto.PublicKey, error) {
+		assert.Equal(t, "key-1", keyID)
+		return publicKey, nil
+	})
+
+	assert.NoError(t, verifier.VerifyRequest(req))
+}
+
+func TestSignAndVerifyRequestRSAPSS(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	privateKeyPemBytes, err := asn1.PemEncode(ctx, privateKey)
+	assert.NoError(t, err)
+
+	signer, err := NewRequestSigner(ctx, privateKeyPemBytes, "key-1")
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/widgets", strings.NewReader(`{"name":"widget"}`))
+	assert.NoError(t, err)
+
+	assert.NoError(t, signer.SignRequest(req))
+	assert.True(t, strings.HasPrefix(req.Header.Get(headerAuthorization), "rsa-pss-sha256 "))
+
+	verifier := NewVerifier(func(keyID string) (crypto.PublicKey, error) {
+		assert.Equal(t, "key-1", keyID)
+		return &privateKey.PublicKey, nil
+	})
+
+	assert.NoError(t, verifier.VerifyRequest(req))
+}
+
+func TestVerifyRequestRejectsStaleTimestamp(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	privateKeyPemBytes, err := asn1.PemEncode(ctx, privateKey)
+	assert.NoError(t, err)
+
+	signer, err := NewRequestSigner(ctx, privateKeyPemBytes, "key-1")
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/widgets", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, signer.SignRequest(req))
+
+	verifier := NewVerifier(func(string) (crypto.PublicKey, error) {
+		return &privateKey.PublicKey, nil
+	}).MaxSkew(-1 * time.Second)
+
+	assert.Error(t, verifier.VerifyRequest(req))
+}
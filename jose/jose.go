@@ -0,0 +1,222 @@
+// Package jose signs, verifies, encrypts, and decrypts payloads using the
+// same PEM-encoded keys that round-trip through the asn1 package, so
+// callers never have to juggle a second key format for JOSE operations.
+package jose
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/pem"
+	"fmt"
+
+	"cryptoutil/asn1"
+
+	josejwt "github.com/go-jose/go-jose/v4"
+)
+
+// Algorithm names the JWS/JWE algorithm a caller wants for a Sign or
+// Encrypt call. The zero value is invalid.
+type Algorithm string
+
+const (
+	RS256 Algorithm = "RS256"
+	RS384 Algorithm = "RS384"
+	RS512 Algorithm = "RS512"
+	PS256 Algorithm = "PS256"
+	PS384 Algorithm = "PS384"
+	PS512 Algorithm = "PS512"
+	ES256 Algorithm = "ES256"
+	ES384 Algorithm = "ES384"
+	ES512 Algorithm = "ES512"
+	EdDSA Algorithm = "EdDSA"
+	HS256 Algorithm = "HS256"
+	HS384 Algorithm = "HS384"
+	HS512 Algorithm = "HS512"
+
+	RSAOAEP Algorithm = "RSA-OAEP"
+	ECDHES  Algorithm = "ECDH-ES"
+)
+
+// Sign produces a JWS compact serialization of payload using alg. For
+// HS256/HS384/HS512, key is used directly as the raw HMAC secret; for
+// every other algorithm, key is a PEM-encoded key decoded via
+// asn1.PemDecode. The algorithm must be compatible with the resulting
+// key's type (and, for ECDSA, its curve). ctx parents the decode span
+// under the caller's trace; pass context.Background() if there isn't
+// one.
+func Sign(ctx context.Context, payload []byte, key []byte, alg Algorithm) (string, error) {
+	signingKey, err := resolveKey(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("decode signing key failed: %w", err)
+	}
+
+	if err := checkSignatureAlgorithm(signingKey, alg); err != nil {
+		return "", err
+	}
+
+	signer, err := josejwt.NewSigner(josejwt.SigningKey{Algorithm: josejwt.SignatureAlgorithm(alg), Key: signingKey}, nil)
+	if err != nil {
+		return "", fmt.Errorf("create JWS signer failed: %w", err)
+	}
+
+	signed, err := signer.Sign(payload)
+	if err != nil {
+		return "", fmt.Errorf("sign payload failed: %w", err)
+	}
+
+	compact, err := signed.CompactSerialize()
+	if err != nil {
+		return "", fmt.Errorf("serialize JWS failed: %w", err)
+	}
+
+	return compact, nil
+}
+
+// Verify checks compact against key and returns the verified payload.
+// For an HS256/HS384/HS512 signature, key is the raw HMAC secret; for
+// every other algorithm, key is a PEM-encoded key decoded via
+// asn1.PemDecode. ctx parents the decode span under the caller's
+// trace; pass context.Background() if there isn't one.
+func Verify(ctx context.Context, compact string, key []byte) ([]byte, error) {
+	verificationKey, err := resolveKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("decode verification key failed: %w", err)
+	}
+
+	signature, err := josejwt.ParseSigned(compact, []josejwt.SignatureAlgorithm{
+		josejwt.RS256, josejwt.RS384, josejwt.RS512,
+		josejwt.PS256, josejwt.PS384, josejwt.PS512,
+		josejwt.ES256, josejwt.ES384, josejwt.ES512,
+		josejwt.EdDSA,
+		josejwt.HS256, josejwt.HS384, josejwt.HS512,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse JWS failed: %w", err)
+	}
+
+	payload, err := signature.Verify(verificationKey)
+	if err != nil {
+		return nil, fmt.Errorf("verify JWS failed: %w", err)
+	}
+
+	return payload, nil
+}
+
+// Encrypt decodes recipient's public key via asn1.PemDecode and produces a
+// JWE compact serialization of payload, wrapping the content-encryption
+// key with alg (RSA-OAEP or ECDH-ES) and encrypting content with enc
+// (e.g. josejwt.A256GCM). ctx parents the decode span under the
+// caller's trace; pass context.Background() if there isn't one.
+func Encrypt(ctx context.Context, payload []byte, recipientPEM []byte, alg Algorithm, enc josejwt.ContentEncryption) (string, error) {
+	recipientKey, err := asn1.PemDecode(ctx, recipientPEM)
+	if err != nil {
+		return "", fmt.Errorf("decode recipient key failed: %w", err)
+	}
+
+	encrypter, err := josejwt.NewEncrypter(enc, josejwt.Recipient{Algorithm: josejwt.KeyAlgorithm(alg), Key: recipientKey}, nil)
+	if err != nil {
+		return "", fmt.Errorf("create JWE encrypter failed: %w", err)
+	}
+
+	encrypted, err := encrypter.Encrypt(payload)
+	if err != nil {
+		return "", fmt.Errorf("encrypt payload failed: %w", err)
+	}
+
+	compact, err := encrypted.CompactSerialize()
+	if err != nil {
+		return "", fmt.Errorf("serialize JWE failed: %w", err)
+	}
+
+	return compact, nil
+}
+
+// Decrypt decodes keyPEM via asn1.PemDecode and decrypts compact, the
+// recipient's own private key matching the one Encrypt used. ctx
+// parents the decode span under the caller's trace; pass
+// context.Background() if there isn't one.
+func Decrypt(ctx context.Context, compact string, keyPEM []byte) ([]byte, error) {
+	key, err := asn1.PemDecode(ctx, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("decode decryption key failed: %w", err)
+	}
+
+	encrypted, err := josejwt.ParseEncrypted(compact,
+		[]josejwt.KeyAlgorithm{josejwt.RSA_OAEP, josejwt.ECDH_ES},
+		[]josejwt.ContentEncryption{josejwt.A128GCM, josejwt.A192GCM, josejwt.A256GCM},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWE failed: %w", err)
+	}
+
+	payload, err := encrypted.Decrypt(key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt JWE failed: %w", err)
+	}
+
+	return payload, nil
+}
+
+// resolveKey decodes key as a PEM-encoded key via asn1.PemDecode, or, if
+// key isn't PEM at all, returns it unchanged as a raw symmetric secret
+// (the only way an HS256/384/512 key can be supplied).
+func resolveKey(ctx context.Context, key []byte) (any, error) {
+	if block, _ := pem.Decode(key); block == nil {
+		return key, nil
+	}
+
+	return asn1.PemDecode(ctx, key)
+}
+
+// checkSignatureAlgorithm rejects alg/key combinations the JWS spec does
+// not allow, so a mismatched curve or key type fails with a clear error
+// before a JWS signer is created.
+func checkSignatureAlgorithm(key any, alg Algorithm) error {
+	switch typed := key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		switch alg {
+		case RS256, RS384, RS512, PS256, PS384, PS512:
+			return nil
+		}
+		return fmt.Errorf("algorithm %s is not valid for an RSA key", alg)
+	case *ecdsa.PrivateKey:
+		return checkECDSACurve(typed.Curve, alg)
+	case *ecdsa.PublicKey:
+		return checkECDSACurve(typed.Curve, alg)
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		if alg != EdDSA {
+			return fmt.Errorf("algorithm %s is not valid for an Ed25519 key", alg)
+		}
+		return nil
+	case []byte:
+		switch alg {
+		case HS256, HS384, HS512:
+			return nil
+		}
+		return fmt.Errorf("algorithm %s is not valid for a symmetric key", alg)
+	default:
+		return fmt.Errorf("unsupported key type for JWS: %T", key)
+	}
+}
+
+// checkECDSACurve enforces the one-to-one mapping the JWS spec requires
+// between curve and algorithm: P-256 with ES256, P-384 with ES384, P-521
+// with ES512.
+func checkECDSACurve(curve elliptic.Curve, alg Algorithm) error {
+	want := map[elliptic.Curve]Algorithm{
+		elliptic.P256(): ES256,
+		elliptic.P384(): ES384,
+		elliptic.P521(): ES512,
+	}[curve]
+
+	if want == "" {
+		return fmt.Errorf("unsupported ECDSA curve for JWS: %s", curve.Params().Name)
+	}
+	if alg != want {
+		return fmt.Errorf("algorithm %s is not valid for curve %s, expected %s", alg, curve.Params().Name, want)
+	}
+	return nil
+}
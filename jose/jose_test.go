@@ -0,0 +1,143 @@
+package jose
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"cryptoutil/asn1"
+	"cryptoutil/telemetry"
+
+	josejwt "github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx     context.Context
+	slogger *slog.Logger
+)
+
+func TestMain(m *testing.M) {
+	startTime := time.Now()
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	telemetryService := telemetry.Init(ctx, startTime, "jose_test", false, false)
+	telemetry.Shutdown(telemetryService)
+	slogger = telemetryService.Slogger
+
+	rc := m.Run()
+	os.Exit(rc)
+}
+
+func TestSignVerifyRSA(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	privateKeyPemBytes, err := asn1.PemEncode(ctx, privateKey)
+	assert.NoError(t, err)
+	publicKeyPemBytes, err := asn1.PemEncode(ctx, &privateKey.PublicKey)
+	assert.NoError(t, err)
+
+	compact, err := Sign(ctx, []byte("hello"), privateKeyPemBytes, PS256)
+	assert.NoError(t, err)
+	slogger.Info("JWS", "compact", compact)
+
+	payload, err := Verify(ctx, compact, publicKeyPemBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), payload)
+}
+
+func TestSignVerifyECDSA(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	privateKeyPemBytes, err := asn1.PemEncode(ctx, privateKey)
+	assert.NoError(t, err)
+	publicKeyPemBytes, err := asn1.PemEncode(ctx, &privateKey.PublicKey)
+	assert.NoError(t, err)
+
+	compact, err := Sign(ctx, []byte("hello"), privateKeyPemBytes, ES256)
+	assert.NoError(t, err)
+
+	payload, err := Verify(ctx, compact, publicKeyPemBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), payload)
+}
+
+func TestSignVerifyHS256(t *testing.T) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	assert.NoError(t, err)
+
+	compact, err := Sign(ctx, []byte("hello"), secret, HS256)
+	assert.NoError(t, err)
+	slogger.Info("JWS", "compact", compact)
+
+	payload, err := Verify(ctx, compact, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), payload)
+
+	_, err = Verify(ctx, compact, []byte("wrong-secret-wrong-secret-wrong!"))
+	assert.Error(t, err)
+}
+
+func TestSignVerifyEdDSA(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	privateKeyPemBytes, err := asn1.PemEncode(ctx, privateKey)
+	assert.NoError(t, err)
+	publicKeyPemBytes, err := asn1.PemEncode(ctx, publicKey)
+	assert.NoError(t, err)
+
+	compact, err := Sign(ctx, []byte("hello"), privateKeyPemBytes, EdDSA)
+	assert.NoError(t, err)
+
+	payload, err := Verify(ctx, compact, publicKeyPemBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), payload)
+}
+
+func TestEncryptDecryptRSAOAEP(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	privateKeyPemBytes, err := asn1.PemEncode(ctx, privateKey)
+	assert.NoError(t, err)
+	publicKeyPemBytes, err := asn1.PemEncode(ctx, &privateKey.PublicKey)
+	assert.NoError(t, err)
+
+	compact, err := Encrypt(ctx, []byte("secret"), publicKeyPemBytes, RSAOAEP, josejwt.A256GCM)
+	assert.NoError(t, err)
+
+	payload, err := Decrypt(ctx, compact, privateKeyPemBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), payload)
+}
+
+func TestEncryptDecryptECDHES(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	privateKeyPemBytes, err := asn1.PemEncode(ctx, privateKey)
+	assert.NoError(t, err)
+	publicKeyPemBytes, err := asn1.PemEncode(ctx, &privateKey.PublicKey)
+	assert.NoError(t, err)
+
+	compact, err := Encrypt(ctx, []byte("secret"), publicKeyPemBytes, ECDHES, josejwt.A256GCM)
+	assert.NoError(t, err)
+
+	payload, err := Decrypt(ctx, compact, privateKeyPemBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), payload)
+}
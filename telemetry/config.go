@@ -0,0 +1,37 @@
+package telemetry
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// OtelGrpcPush is the OTLP/gRPC collector endpoint InitMetrics and
+// InitTracing push to when called with enableOtel set.
+const OtelGrpcPush = "localhost:4317"
+
+// MetricsTimeout is the periodic reader export interval for both the
+// GRPC and stdout metric readers.
+const MetricsTimeout = 15 * time.Second
+
+// otelMetricsTracesAttributes tags every metric and span this service
+// emits with its resource identity.
+var otelMetricsTracesAttributes = []attribute.KeyValue{
+	attribute.String("service.name", "cryptoutil"),
+}
+
+// ifErrorLogAndExit logs format (with err interpolated) and exits the
+// process if err is non-nil. Telemetry bootstrap failures are treated as
+// fatal rather than threaded through every Init call as an error return,
+// since a process that can't stand up its own observability shouldn't
+// run unobserved.
+func ifErrorLogAndExit(format string, err error) {
+	if err == nil {
+		return
+	}
+	slog.Error(fmt.Sprintf(format, err))
+	os.Exit(1)
+}
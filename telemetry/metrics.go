@@ -10,6 +10,12 @@ import (
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/sdk/metric"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
 )
 
 func InitMetrics(ctx context.Context, enableOtel bool, enableStdout bool) *metric.MeterProvider {
@@ -35,6 +41,38 @@ func InitMetrics(ctx context.Context, enableOtel bool, enableStdout bool) *metri
 	return metric.NewMeterProvider(metricsOptions...)
 }
 
+// InitTracing builds a TracerProvider the same way InitMetrics builds a
+// MeterProvider: GRPC and/or stdout span exporters against the same
+// resource attributes and OTLP push endpoint. It also installs the
+// resulting provider and a W3C trace-context + baggage propagator as the
+// process-wide defaults, so packages can call otel.Tracer(name) without
+// any further wiring.
+func InitTracing(ctx context.Context, enableOtel bool, enableStdout bool) *trace.TracerProvider {
+	var tracingOptions []trace.TracerProviderOption
+
+	otelMeterTracerTags, err := resource.New(ctx, resource.WithAttributes(otelMetricsTracesAttributes...))
+	ifErrorLogAndExit("create Otel GRPC tracing resource failed: %v", err)
+	tracingOptions = append(tracingOptions, trace.WithResource(otelMeterTracerTags))
+
+	if enableOtel {
+		otelGrpcTraces, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(OtelGrpcPush), otlptracegrpc.WithInsecure())
+		ifErrorLogAndExit("create Otel GRPC tracing failed: %v", err)
+		tracingOptions = append(tracingOptions, trace.WithSpanProcessor(trace.NewBatchSpanProcessor(otelGrpcTraces)))
+	}
+
+	if enableStdout {
+		stdoutTraces, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		ifErrorLogAndExit("create STDOUT tracing failed: %v", err)
+		tracingOptions = append(tracingOptions, trace.WithSpanProcessor(trace.NewBatchSpanProcessor(stdoutTraces)))
+	}
+
+	tracerProvider := trace.NewTracerProvider(tracingOptions...)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tracerProvider
+}
+
 func DoMetricExample(ctx context.Context, telemetryService *Service) {
 	exampleMetricsScope := telemetryService.MetricsProvider.Meter("example-scope")
 
@@ -0,0 +1,46 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Service bundles a component's structured logger and telemetry
+// providers, built once at startup by Init and torn down by Shutdown.
+type Service struct {
+	Slogger         *slog.Logger
+	MetricsProvider *metric.MeterProvider
+	TracesProvider  *trace.TracerProvider
+}
+
+// Init builds a Service for serviceName, pushing metrics and traces to
+// the OTLP collector when enableOtel is set and mirroring them to stdout
+// when enableStdout is set.
+func Init(ctx context.Context, startTime time.Time, serviceName string, enableOtel bool, enableStdout bool) *Service {
+	return &Service{
+		Slogger:         slog.Default().With("service", serviceName, "start_time", startTime),
+		MetricsProvider: InitMetrics(ctx, enableOtel, enableStdout),
+		TracesProvider:  InitTracing(ctx, enableOtel, enableStdout),
+	}
+}
+
+// Shutdown flushes and stops service's providers.
+func Shutdown(service *Service) {
+	ctx := context.Background()
+
+	if service.MetricsProvider != nil {
+		if err := service.MetricsProvider.Shutdown(ctx); err != nil {
+			service.Slogger.Error("shutdown metrics provider failed", "error", err)
+		}
+	}
+
+	if service.TracesProvider != nil {
+		if err := service.TracesProvider.Shutdown(ctx); err != nil {
+			service.Slogger.Error("shutdown traces provider failed", "error", err)
+		}
+	}
+}